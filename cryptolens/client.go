@@ -0,0 +1,362 @@
+package cryptolens
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultBaseURL is the base URL used by a Client created with NewClient,
+// and by the DefaultClient used by the package-level KeyActivate,
+// KeyDeactivate, and KeyValidate functions.
+const defaultBaseURL = "https://app.cryptolens.io"
+
+// RetryPolicy controls how a Client retries requests that fail with a
+// network error or a 429/5xx response.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of retries attempted after the
+	// initial request fails. A value of 0 disables retries.
+	MaxRetries int
+
+	// BaseDelay is the delay before the first retry. Subsequent retries
+	// back off exponentially from this value. If zero, a 500ms default is
+	// used.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the backoff delay between retries. If zero, a 30s
+	// default is used.
+	MaxDelay time.Duration
+}
+
+// RequestInfo describes a single HTTP request made by a Client, and is
+// passed to Client.OnRequest after the request completes.
+type RequestInfo struct {
+	// Endpoint is the Web API method that was called, e.g. "Activate".
+	Endpoint string
+
+	// Attempt is the 1-based attempt number; a value greater than 1
+	// indicates the request was retried.
+	Attempt int
+
+	// Duration is how long the attempt took.
+	Duration time.Duration
+
+	// Err is the error returned by the attempt, or nil on success.
+	Err error
+}
+
+// Client controls how this package talks to the Cryptolens Web API: which
+// underlying http.Client to use, which base URL to send requests to, and
+// how to retry failed requests. The zero value is a usable client that
+// talks to the production API with no retries; use NewClient for a client
+// with sensible retry defaults.
+type Client struct {
+	// HTTPClient is the http.Client used to make requests. If nil,
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+
+	// BaseURL overrides the Cryptolens Web API origin, e.g. for an
+	// on-premise deployment or a test double. If empty, defaultBaseURL is
+	// used.
+	BaseURL string
+
+	// UserAgent, if set, is sent as the User-Agent request header.
+	UserAgent string
+
+	// RetryPolicy controls retry behavior for requests that fail with a
+	// network error or a 429/5xx response. The zero value disables
+	// retries.
+	RetryPolicy RetryPolicy
+
+	// OnRequest, if set, is called after every attempt of every request,
+	// successful or not, so callers can collect telemetry such as request
+	// duration and retry counts.
+	OnRequest func(RequestInfo)
+}
+
+// NewClient returns a Client configured with a 30 second request timeout
+// and a retry policy of up to 3 retries with exponential backoff, talking
+// to the production Cryptolens Web API.
+func NewClient() *Client {
+	return &Client{
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+		RetryPolicy: RetryPolicy{
+			MaxRetries: 3,
+			BaseDelay:  500 * time.Millisecond,
+			MaxDelay:   8 * time.Second,
+		},
+	}
+}
+
+// DefaultClient is the Client used by the package-level KeyActivate,
+// KeyActivateContext, KeyDeactivate, KeyDeactivateContext, KeyValidate, and
+// KeyValidateContext functions.
+var DefaultClient = NewClient()
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+func (c *Client) baseURL() string {
+	if c.BaseURL != "" {
+		return c.BaseURL
+	}
+	return defaultBaseURL
+}
+
+// do posts data to the named Web API method, e.g. "key/Activate" or
+// "data/AddDataObject", retrying according to c.RetryPolicy on network
+// errors and 429/5xx responses, and returns the raw response body.
+func (c *Client) do(ctx context.Context, endpoint string, data url.Values) ([]byte, error) {
+	requestURL := c.baseURL() + "/api/" + endpoint
+
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		body, status, err := c.doOnce(ctx, requestURL, data)
+		duration := time.Since(start)
+
+		if err == nil && isRetryableStatus(status) {
+			err = fmt.Errorf("cryptolens: %s: unexpected status %d", endpoint, status)
+		}
+
+		if c.OnRequest != nil {
+			c.OnRequest(RequestInfo{Endpoint: endpoint, Attempt: attempt, Duration: duration, Err: err})
+		}
+
+		if err == nil {
+			return body, nil
+		}
+		lastErr = err
+
+		if attempt > c.RetryPolicy.MaxRetries {
+			return nil, lastErr
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(c.RetryPolicy.backoff(attempt)):
+		}
+	}
+}
+
+func (c *Client) doOnce(ctx context.Context, requestURL string, data url.Values) ([]byte, int, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", requestURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
+	response, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer response.Body.Close()
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, response.StatusCode, err
+	}
+
+	return body, response.StatusCode, nil
+}
+
+// isRetryableStatus reports whether status indicates a request that is
+// worth retrying: rate limiting or a server error.
+func isRetryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// backoff returns the delay before the given attempt (1-based), applying
+// exponential backoff from BaseDelay up to MaxDelay, with up to 50% jitter.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	base := p.BaseDelay
+	if base <= 0 {
+		base = 500 * time.Millisecond
+	}
+	max := p.MaxDelay
+	if max <= 0 {
+		max = 30 * time.Second
+	}
+
+	delay := base * time.Duration(1<<uint(attempt-1))
+	if delay <= 0 || delay > max {
+		delay = max
+	}
+
+	jitter := time.Duration(rand.Int63n(int64(delay)/2 + 1))
+	return delay/2 + jitter
+}
+
+// KeyActivate performs a request to the key method Activate in Cryptolens
+// Web API 3, using c's HTTPClient, BaseURL, and RetryPolicy.
+//
+// Note that KeyActivate does not check the cryptographic signature of the
+// returned response, or any other information such as the expiration
+// field. Checking the cryptographic signature can be done using the
+// HasValidSignature() or HasValidEd25519Signature() method.
+func (c *Client) KeyActivate(ctx context.Context, token string, args KeyActivateArguments) (LicenseKey, error) {
+	data := url.Values{}
+	data.Add("token", token)
+	data.Add("ProductId", strconv.Itoa(args.ProductId))
+	data.Add("Key", args.Key)
+	data.Add("MachineCode", args.MachineCode)
+	data.Add("FieldsToReturn", strconv.Itoa(args.FieldsToReturn))
+	data.Add("FloatingTimeInterval", strconv.Itoa(args.FloatingTimeInterval))
+	data.Add("MaxOverdraft", strconv.Itoa(args.MaxOverdraft))
+
+	signMethod := args.SignMethod
+	if signMethod == 0 {
+		signMethod = SignMethodRSA
+	}
+	data.Add("Sign", "true")
+	data.Add("SignMethod", strconv.Itoa(int(signMethod)))
+
+	body, err := c.do(ctx, "key/Activate", data)
+	if err != nil {
+		return LicenseKey{}, err
+	}
+
+	var r activateResponse
+	if err := json.Unmarshal(body, &r); err != nil {
+		return LicenseKey{}, err
+	}
+	if r.Result != 0 {
+		return LicenseKey{}, errors.New(r.Message)
+	}
+
+	licenseKeyBytes, signatureBytes, err := parseActivateResponse(&r)
+	if err != nil {
+		return LicenseKey{}, err
+	}
+
+	return buildLicenseKey(licenseKeyBytes, signatureBytes, signMethod)
+}
+
+// KeyDeactivate performs a request to the key method Deactivate in
+// Cryptolens Web API 3, using c's HTTPClient, BaseURL, and RetryPolicy. It
+// releases the activation slot that args.MachineCode occupies against
+// args.Key, so that it counts towards MaxNoOfMachines again.
+func (c *Client) KeyDeactivate(ctx context.Context, token string, args KeyDeactivateArguments) error {
+	data := url.Values{}
+	data.Add("token", token)
+	data.Add("ProductId", strconv.Itoa(args.ProductId))
+	data.Add("Key", args.Key)
+	data.Add("MachineCode", args.MachineCode)
+	data.Add("FloatingTimeInterval", strconv.Itoa(args.FloatingTimeInterval))
+
+	body, err := c.do(ctx, "key/Deactivate", data)
+	if err != nil {
+		return err
+	}
+
+	var r basicResponse
+	if err := json.Unmarshal(body, &r); err != nil {
+		return err
+	}
+	if r.Result != 0 {
+		return errors.New(r.Message)
+	}
+
+	return nil
+}
+
+// KeyValidate performs a request to the key method Validate in Cryptolens
+// Web API 3, using c's HTTPClient, BaseURL, and RetryPolicy. Unlike
+// KeyActivate, it does not consume an activation slot, which makes it
+// suitable for lightweight, frequent re-checks of a license that has
+// already been activated.
+//
+// Note that KeyValidate does not check the cryptographic signature of the
+// returned response. Checking the cryptographic signature can be done
+// using the HasValidSignature() or HasValidEd25519Signature() method.
+func (c *Client) KeyValidate(ctx context.Context, token string, args KeyValidateArguments) (LicenseKey, error) {
+	data := url.Values{}
+	data.Add("token", token)
+	data.Add("ProductId", strconv.Itoa(args.ProductId))
+	data.Add("Key", args.Key)
+	data.Add("MachineCode", args.MachineCode)
+	data.Add("FieldsToReturn", strconv.Itoa(args.FieldsToReturn))
+
+	signMethod := args.SignMethod
+	if signMethod == 0 {
+		signMethod = SignMethodRSA
+	}
+	data.Add("Sign", "true")
+	data.Add("SignMethod", strconv.Itoa(int(signMethod)))
+
+	body, err := c.do(ctx, "key/Validate", data)
+	if err != nil {
+		return LicenseKey{}, err
+	}
+
+	var r activateResponse
+	if err := json.Unmarshal(body, &r); err != nil {
+		return LicenseKey{}, err
+	}
+	if r.Result != 0 {
+		return LicenseKey{}, errors.New(r.Message)
+	}
+
+	licenseKeyBytes, signatureBytes, err := parseActivateResponse(&r)
+	if err != nil {
+		return LicenseKey{}, err
+	}
+
+	return buildLicenseKey(licenseKeyBytes, signatureBytes, signMethod)
+}
+
+// KeyActivate performs a request to the key method Activate in Cryptolens Web API 3
+// using DefaultClient. The parameter token is an access token and args is a struct
+// with additional parameters, some of which are optional. See
+// KeyActivateArguments for more information.
+func KeyActivate(token string, args KeyActivateArguments) (LicenseKey, error) {
+	return KeyActivateContext(context.Background(), token, args)
+}
+
+// KeyActivateContext is KeyActivate, using DefaultClient, with a context
+// that can cancel the request or impose a deadline.
+func KeyActivateContext(ctx context.Context, token string, args KeyActivateArguments) (LicenseKey, error) {
+	return DefaultClient.KeyActivate(ctx, token, args)
+}
+
+// KeyDeactivate performs a request to the key method Deactivate in
+// Cryptolens Web API 3 using DefaultClient. See KeyDeactivateArguments for
+// more information.
+func KeyDeactivate(token string, args KeyDeactivateArguments) error {
+	return KeyDeactivateContext(context.Background(), token, args)
+}
+
+// KeyDeactivateContext is KeyDeactivate, using DefaultClient, with a
+// context that can cancel the request or impose a deadline.
+func KeyDeactivateContext(ctx context.Context, token string, args KeyDeactivateArguments) error {
+	return DefaultClient.KeyDeactivate(ctx, token, args)
+}
+
+// KeyValidate performs a request to the key method Validate in Cryptolens
+// Web API 3 using DefaultClient. See KeyValidateArguments for more
+// information.
+func KeyValidate(token string, args KeyValidateArguments) (LicenseKey, error) {
+	return KeyValidateContext(context.Background(), token, args)
+}
+
+// KeyValidateContext is KeyValidate, using DefaultClient, with a context
+// that can cancel the request or impose a deadline.
+func KeyValidateContext(ctx context.Context, token string, args KeyValidateArguments) (LicenseKey, error) {
+	return DefaultClient.KeyValidate(ctx, token, args)
+}
@@ -9,9 +9,6 @@ import (
 	"encoding/json"
 	"encoding/xml"
 	"math/big"
-	"net/http"
-	"net/url"
-	"strconv"
 	"strings"
 	"time"
 )
@@ -102,8 +99,25 @@ type LicenseKey struct {
 
 	licenseKeyBytes []byte
 	signatureBytes  []byte
+	signMethod      SignMethod
 }
 
+// SignMethod identifies the algorithm used by the Cryptolens Web API to
+// sign a license key response.
+type SignMethod int
+
+const (
+	// SignMethodRSA is the default signing algorithm: RSA-SHA256 with
+	// PKCS#1 v1.5 padding. Signatures produced with this method are
+	// verified using HasValidSignature.
+	SignMethodRSA SignMethod = 1
+
+	// SignMethodEd25519 signs the response using Ed25519. Signatures
+	// produced with this method are verified using
+	// HasValidEd25519Signature.
+	SignMethodEd25519 SignMethod = 2
+)
+
 // Customer describes an individual customer. The existing customers can be shown
 // on https://app.cryptolens.io/Customer when logged in.
 type Customer struct {
@@ -356,29 +370,12 @@ type KeyActivateArguments struct {
 	// active. The default value of 0 disables overdraft devices.
 	// See https://app.cryptolens.io/docs/api/v3/Activate for more details.
 	MaxOverdraft int
-}
-
-// KeyActivate performs a request to the key method Activate in Cryptolens Web API 3.
-// The parameter token is an access token and args is a struct with additional
-// parameters, some of which are optional. See KeyActivateArguments for more
-// information.
-//
-// Note that KeyActivate does not check the cryptographic signature of the
-// returned response, or any other information such as the expiration field.
-// Checking the cryptographic signature can be done using the HasValidSignature()
-// method.
-func KeyActivate(token string, args KeyActivateArguments) (LicenseKey, error) {
-	activateResponse, err := makeActivateRequest(token, args)
-	if err != nil {
-		return LicenseKey{}, err
-	}
-
-	licenseKeyBytes, signatureBytes, err := parseActivateResponse(&activateResponse)
-	if err != nil {
-		return LicenseKey{}, err
-	}
 
-	return buildLicenseKey(licenseKeyBytes, signatureBytes)
+	// SignMethod selects the algorithm used to sign the response. The zero
+	// value defaults to SignMethodRSA, verified with HasValidSignature.
+	// Use SignMethodEd25519 to request a response verified with
+	// HasValidEd25519Signature instead.
+	SignMethod SignMethod
 }
 
 // KeyFromBytes takes a byte slice and attempts to parse this into a LicenseKey.
@@ -397,39 +394,7 @@ func KeyFromBytes(b []byte) (LicenseKey, error) {
 		return LicenseKey{}, err
 	}
 
-	return buildLicenseKey(licenseKeyBytes, signatureBytes)
-}
-
-func makeActivateRequest(token string, args KeyActivateArguments) (activateResponse, error) {
-	var http http.Client
-
-	// From KeyActivateArguments struct
-	data := url.Values{}
-	data.Add("token", token)
-	data.Add("ProductId", strconv.Itoa(args.ProductId))
-	data.Add("Key", args.Key)
-	data.Add("MachineCode", args.MachineCode)
-	data.Add("FieldsToReturn", strconv.Itoa(args.FieldsToReturn))
-	data.Add("FloatingTimeInterval", strconv.Itoa(args.FloatingTimeInterval))
-	data.Add("MaxOverdraft", strconv.Itoa(args.MaxOverdraft))
-
-	// Hardcoded by the library
-	data.Add("Sign", "true")
-	data.Add("SignMethod", "1")
-
-	response, err := http.PostForm("https://app.cryptolens.io/api/key/Activate", data)
-	if err != nil {
-		return activateResponse{}, err
-	}
-
-	dec := json.NewDecoder(response.Body)
-	var r activateResponse
-	err = dec.Decode(&r)
-	if err != nil {
-		return activateResponse{}, err
-	}
-
-	return r, nil
+	return buildLicenseKey(licenseKeyBytes, signatureBytes, SignMethodRSA)
 }
 
 func parseActivateResponse(response *activateResponse) ([]byte, []byte, error) {
@@ -446,7 +411,7 @@ func parseActivateResponse(response *activateResponse) ([]byte, []byte, error) {
 	return licenseKeyBytes, signatureBytes, nil
 }
 
-func buildLicenseKey(licenseKeyBytes []byte, signatureBytes []byte) (LicenseKey, error) {
+func buildLicenseKey(licenseKeyBytes []byte, signatureBytes []byte, signMethod SignMethod) (LicenseKey, error) {
 	var k LicenseKey
 	err := json.Unmarshal(licenseKeyBytes, &k)
 	if err != nil {
@@ -455,6 +420,7 @@ func buildLicenseKey(licenseKeyBytes []byte, signatureBytes []byte) (LicenseKey,
 
 	k.licenseKeyBytes = licenseKeyBytes
 	k.signatureBytes = signatureBytes
+	k.signMethod = signMethod
 
 	return k, nil
 }
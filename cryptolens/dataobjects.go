@@ -0,0 +1,350 @@
+package cryptolens
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+)
+
+// dataObjectResponse is the response shape for Web API data methods that
+// return a single, updated data object.
+type dataObjectResponse struct {
+	Result     int        `json:"result"`
+	Message    string     `json:"message"`
+	DataObject DataObject `json:"dataObject"`
+}
+
+// dataObjectListResponse is the response shape for ListDataObjects.
+type dataObjectListResponse struct {
+	Result      int          `json:"result"`
+	Message     string       `json:"message"`
+	DataObjects []DataObject `json:"dataObjects"`
+}
+
+// AddDataObjectArguments contains extra and optional arguments for the
+// AddDataObject function.
+type AddDataObjectArguments struct {
+	// ProductId is the id of the product which the key belongs to
+	ProductId int
+
+	// Key is the license key string that the data object will be attached to.
+	Key string
+
+	// Name is the name of the new data object.
+	Name string
+
+	// IntValue is the initial integer value of the data object.
+	IntValue int
+
+	// StringValue is the initial string value of the data object.
+	StringValue string
+}
+
+// AddDataObject performs a request to the data method AddDataObject in
+// Cryptolens Web API 3, creating a new data object attached to args.Key and
+// returning it.
+func (c *Client) AddDataObject(ctx context.Context, token string, args AddDataObjectArguments) (DataObject, error) {
+	data := url.Values{}
+	data.Add("token", token)
+	data.Add("ProductId", strconv.Itoa(args.ProductId))
+	data.Add("Key", args.Key)
+	data.Add("Name", args.Name)
+	data.Add("IntValue", strconv.Itoa(args.IntValue))
+	data.Add("StringValue", args.StringValue)
+
+	return c.doDataObjectRequest(ctx, "data/AddDataObject", data)
+}
+
+// ListDataObjectsArguments contains extra and optional arguments for the
+// ListDataObjects function.
+type ListDataObjectsArguments struct {
+	// ProductId is the id of the product which the key belongs to
+	ProductId int
+
+	// Key is the license key string whose data objects should be listed.
+	Key string
+}
+
+// ListDataObjects performs a request to the data method ListDataObjects in
+// Cryptolens Web API 3, returning every data object attached to args.Key.
+func (c *Client) ListDataObjects(ctx context.Context, token string, args ListDataObjectsArguments) ([]DataObject, error) {
+	data := url.Values{}
+	data.Add("token", token)
+	data.Add("ProductId", strconv.Itoa(args.ProductId))
+	data.Add("Key", args.Key)
+
+	body, err := c.do(ctx, "data/ListDataObjects", data)
+	if err != nil {
+		return nil, err
+	}
+
+	var r dataObjectListResponse
+	if err := json.Unmarshal(body, &r); err != nil {
+		return nil, err
+	}
+	if r.Result != 0 {
+		return nil, errors.New(r.Message)
+	}
+
+	return r.DataObjects, nil
+}
+
+// RemoveDataObjectArguments contains extra and optional arguments for the
+// RemoveDataObject function.
+type RemoveDataObjectArguments struct {
+	// ProductId is the id of the product which the key belongs to
+	ProductId int
+
+	// Key is the license key string that the data object is attached to.
+	Key string
+
+	// DataObjectId is the id of the data object to remove.
+	DataObjectId int
+}
+
+// RemoveDataObject performs a request to the data method RemoveDataObject
+// in Cryptolens Web API 3, deleting the data object identified by
+// args.DataObjectId.
+func (c *Client) RemoveDataObject(ctx context.Context, token string, args RemoveDataObjectArguments) error {
+	data := url.Values{}
+	data.Add("token", token)
+	data.Add("ProductId", strconv.Itoa(args.ProductId))
+	data.Add("Key", args.Key)
+	data.Add("Id", strconv.Itoa(args.DataObjectId))
+
+	body, err := c.do(ctx, "data/RemoveDataObject", data)
+	if err != nil {
+		return err
+	}
+
+	var r basicResponse
+	if err := json.Unmarshal(body, &r); err != nil {
+		return err
+	}
+	if r.Result != 0 {
+		return errors.New(r.Message)
+	}
+
+	return nil
+}
+
+// SetIntValueArguments contains extra and optional arguments for the
+// SetIntValue function.
+type SetIntValueArguments struct {
+	// ProductId is the id of the product which the key belongs to
+	ProductId int
+
+	// Key is the license key string that the data object is attached to.
+	Key string
+
+	// DataObjectId is the id of the data object to update.
+	DataObjectId int
+
+	// IntValue is the value to set.
+	IntValue int
+
+	// EnableExactChecks requires ExpectedIntValue to match the data
+	// object's current value for the update to take effect, so that a
+	// client updating a usage counter can detect races with other writers.
+	EnableExactChecks bool
+
+	// ExpectedIntValue is the value the data object is expected to
+	// currently hold. Only used when EnableExactChecks is true.
+	ExpectedIntValue int
+}
+
+// SetIntValue performs a request to the data method SetIntValue in
+// Cryptolens Web API 3, setting the data object identified by
+// args.DataObjectId to args.IntValue.
+func (c *Client) SetIntValue(ctx context.Context, token string, args SetIntValueArguments) (DataObject, error) {
+	data := exactCheckValues(args.ProductId, args.Key, args.DataObjectId, args.EnableExactChecks, args.ExpectedIntValue)
+	data.Add("token", token)
+	data.Add("IntValue", strconv.Itoa(args.IntValue))
+
+	return c.doDataObjectRequest(ctx, "data/SetIntValue", data)
+}
+
+// SetStringValueArguments contains extra and optional arguments for the
+// SetStringValue function.
+type SetStringValueArguments struct {
+	// ProductId is the id of the product which the key belongs to
+	ProductId int
+
+	// Key is the license key string that the data object is attached to.
+	Key string
+
+	// DataObjectId is the id of the data object to update.
+	DataObjectId int
+
+	// StringValue is the value to set.
+	StringValue string
+}
+
+// SetStringValue performs a request to the data method SetStringValue in
+// Cryptolens Web API 3, setting the data object identified by
+// args.DataObjectId to args.StringValue.
+func (c *Client) SetStringValue(ctx context.Context, token string, args SetStringValueArguments) (DataObject, error) {
+	data := url.Values{}
+	data.Add("token", token)
+	data.Add("ProductId", strconv.Itoa(args.ProductId))
+	data.Add("Key", args.Key)
+	data.Add("Id", strconv.Itoa(args.DataObjectId))
+	data.Add("StringValue", args.StringValue)
+
+	return c.doDataObjectRequest(ctx, "data/SetStringValue", data)
+}
+
+// IncrementIntValueArguments contains extra and optional arguments for the
+// IncrementIntValue function.
+type IncrementIntValueArguments struct {
+	// ProductId is the id of the product which the key belongs to
+	ProductId int
+
+	// Key is the license key string that the data object is attached to.
+	Key string
+
+	// DataObjectId is the id of the data object to update.
+	DataObjectId int
+
+	// IntValue is the amount to increment the data object's value by.
+	IntValue int
+
+	// EnableExactChecks requires ExpectedIntValue to match the data
+	// object's current value for the update to take effect, so that a
+	// client updating a usage counter can detect races with other writers.
+	EnableExactChecks bool
+
+	// ExpectedIntValue is the value the data object is expected to
+	// currently hold. Only used when EnableExactChecks is true.
+	ExpectedIntValue int
+}
+
+// IncrementIntValue performs a request to the data method IncrementIntValue
+// in Cryptolens Web API 3, adding args.IntValue to the current value of the
+// data object identified by args.DataObjectId.
+func (c *Client) IncrementIntValue(ctx context.Context, token string, args IncrementIntValueArguments) (DataObject, error) {
+	data := exactCheckValues(args.ProductId, args.Key, args.DataObjectId, args.EnableExactChecks, args.ExpectedIntValue)
+	data.Add("token", token)
+	data.Add("IntValue", strconv.Itoa(args.IntValue))
+
+	return c.doDataObjectRequest(ctx, "data/IncrementIntValue", data)
+}
+
+// DecrementIntValueArguments contains extra and optional arguments for the
+// DecrementIntValue function.
+type DecrementIntValueArguments struct {
+	// ProductId is the id of the product which the key belongs to
+	ProductId int
+
+	// Key is the license key string that the data object is attached to.
+	Key string
+
+	// DataObjectId is the id of the data object to update.
+	DataObjectId int
+
+	// IntValue is the amount to decrement the data object's value by.
+	IntValue int
+
+	// EnableExactChecks requires ExpectedIntValue to match the data
+	// object's current value for the update to take effect, so that a
+	// client updating a usage counter can detect races with other writers.
+	EnableExactChecks bool
+
+	// ExpectedIntValue is the value the data object is expected to
+	// currently hold. Only used when EnableExactChecks is true.
+	ExpectedIntValue int
+}
+
+// DecrementIntValue performs a request to the data method DecrementIntValue
+// in Cryptolens Web API 3, subtracting args.IntValue from the current value
+// of the data object identified by args.DataObjectId.
+func (c *Client) DecrementIntValue(ctx context.Context, token string, args DecrementIntValueArguments) (DataObject, error) {
+	data := exactCheckValues(args.ProductId, args.Key, args.DataObjectId, args.EnableExactChecks, args.ExpectedIntValue)
+	data.Add("token", token)
+	data.Add("IntValue", strconv.Itoa(args.IntValue))
+
+	return c.doDataObjectRequest(ctx, "data/DecrementIntValue", data)
+}
+
+// exactCheckValues builds the url.Values shared by SetIntValue,
+// IncrementIntValue, and DecrementIntValue, including the
+// EnableExactChecks/ExpectedIntValue pair used to detect concurrent writers.
+func exactCheckValues(productId int, key string, dataObjectId int, enableExactChecks bool, expectedIntValue int) url.Values {
+	data := url.Values{}
+	data.Add("ProductId", strconv.Itoa(productId))
+	data.Add("Key", key)
+	data.Add("Id", strconv.Itoa(dataObjectId))
+	data.Add("EnableExactChecks", strconv.FormatBool(enableExactChecks))
+	data.Add("ExpectedIntValue", strconv.Itoa(expectedIntValue))
+	return data
+}
+
+// doDataObjectRequest posts data to endpoint and decodes the resulting
+// dataObjectResponse.
+func (c *Client) doDataObjectRequest(ctx context.Context, endpoint string, data url.Values) (DataObject, error) {
+	body, err := c.do(ctx, endpoint, data)
+	if err != nil {
+		return DataObject{}, err
+	}
+
+	var r dataObjectResponse
+	if err := json.Unmarshal(body, &r); err != nil {
+		return DataObject{}, err
+	}
+	if r.Result != 0 {
+		return DataObject{}, errors.New(r.Message)
+	}
+
+	return r.DataObject, nil
+}
+
+// AddDataObject performs a request to the data method AddDataObject in
+// Cryptolens Web API 3 using DefaultClient. See AddDataObjectArguments for
+// more information.
+func AddDataObject(token string, args AddDataObjectArguments) (DataObject, error) {
+	return DefaultClient.AddDataObject(context.Background(), token, args)
+}
+
+// ListDataObjects performs a request to the data method ListDataObjects in
+// Cryptolens Web API 3 using DefaultClient. See ListDataObjectsArguments
+// for more information.
+func ListDataObjects(token string, args ListDataObjectsArguments) ([]DataObject, error) {
+	return DefaultClient.ListDataObjects(context.Background(), token, args)
+}
+
+// RemoveDataObject performs a request to the data method RemoveDataObject
+// in Cryptolens Web API 3 using DefaultClient. See
+// RemoveDataObjectArguments for more information.
+func RemoveDataObject(token string, args RemoveDataObjectArguments) error {
+	return DefaultClient.RemoveDataObject(context.Background(), token, args)
+}
+
+// SetIntValue performs a request to the data method SetIntValue in
+// Cryptolens Web API 3 using DefaultClient. See SetIntValueArguments for
+// more information.
+func SetIntValue(token string, args SetIntValueArguments) (DataObject, error) {
+	return DefaultClient.SetIntValue(context.Background(), token, args)
+}
+
+// SetStringValue performs a request to the data method SetStringValue in
+// Cryptolens Web API 3 using DefaultClient. See SetStringValueArguments for
+// more information.
+func SetStringValue(token string, args SetStringValueArguments) (DataObject, error) {
+	return DefaultClient.SetStringValue(context.Background(), token, args)
+}
+
+// IncrementIntValue performs a request to the data method IncrementIntValue
+// in Cryptolens Web API 3 using DefaultClient. See
+// IncrementIntValueArguments for more information.
+func IncrementIntValue(token string, args IncrementIntValueArguments) (DataObject, error) {
+	return DefaultClient.IncrementIntValue(context.Background(), token, args)
+}
+
+// DecrementIntValue performs a request to the data method DecrementIntValue
+// in Cryptolens Web API 3 using DefaultClient. See
+// DecrementIntValueArguments for more information.
+func DecrementIntValue(token string, args DecrementIntValueArguments) (DataObject, error) {
+	return DefaultClient.DecrementIntValue(context.Background(), token, args)
+}
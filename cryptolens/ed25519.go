@@ -0,0 +1,57 @@
+package cryptolens
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"strings"
+)
+
+// HasValidEd25519Signature verifies the cryptographic signature of the
+// license key against publicKey, using Ed25519 instead of the RSA scheme
+// used by HasValidSignature. Use this for license keys activated with
+// KeyActivateArguments.SignMethod set to SignMethodEd25519.
+//
+// The argument publicKey may be encoded as a hex string, a base64 string,
+// or a PEM-encoded "PUBLIC KEY" block, such as the ones produced by
+// OpenSSL, so that keys generated outside of Cryptolens's own tooling do
+// not need to be converted first.
+func (licenseKey *LicenseKey) HasValidEd25519Signature(publicKey string) bool {
+	key, err := parseEd25519PublicKey(publicKey)
+	if err != nil {
+		return false
+	}
+
+	return ed25519.Verify(key, licenseKey.licenseKeyBytes, licenseKey.signatureBytes)
+}
+
+// parseEd25519PublicKey accepts an Ed25519 public key encoded as hex,
+// base64, or a PEM "PUBLIC KEY" block and returns the raw key.
+func parseEd25519PublicKey(publicKey string) (ed25519.PublicKey, error) {
+	publicKey = strings.TrimSpace(publicKey)
+
+	if block, _ := pem.Decode([]byte(publicKey)); block != nil {
+		parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		key, ok := parsed.(ed25519.PublicKey)
+		if !ok {
+			return nil, errors.New("cryptolens: PEM block does not contain an Ed25519 public key")
+		}
+		return key, nil
+	}
+
+	if raw, err := hex.DecodeString(publicKey); err == nil && len(raw) == ed25519.PublicKeySize {
+		return ed25519.PublicKey(raw), nil
+	}
+
+	if raw, err := base64.StdEncoding.DecodeString(publicKey); err == nil && len(raw) == ed25519.PublicKeySize {
+		return ed25519.PublicKey(raw), nil
+	}
+
+	return nil, errors.New("cryptolens: unrecognized Ed25519 public key encoding")
+}
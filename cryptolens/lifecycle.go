@@ -0,0 +1,170 @@
+package cryptolens
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// KeyDeactivateArguments contains extra and optional arguments for the
+// KeyDeactivate function.
+type KeyDeactivateArguments struct {
+	// ProductId is the id of the product which the key belongs to
+	ProductId int
+
+	// Key is the license key string, e.g. ABCD-1234-DCBA-4321
+	Key string
+
+	// MachineCode is the unique identifier for the device that was passed
+	// to KeyActivate when the device was activated.
+	MachineCode string
+
+	// FloatingTimeInterval should be set to the same value that was used
+	// when activating, if floating licensing was used.
+	// See https://app.cryptolens.io/docs/api/v3/Deactivate for more details.
+	FloatingTimeInterval int
+}
+
+// basicResponse is the response shape for Web API methods that do not
+// return a signed license key, such as Deactivate.
+type basicResponse struct {
+	Result  int    `json:"result"`
+	Message string `json:"message"`
+}
+
+// KeyValidateArguments contains extra and optional arguments for the
+// KeyValidate function.
+type KeyValidateArguments struct {
+	// ProductId is the id of the product which the key belongs to
+	ProductId int
+
+	// Key is the license key string, e.g. ABCD-1234-DCBA-4321
+	Key string
+
+	// MachineCode, if set, is checked against the machines that have
+	// already activated this license, so that keys with a machine limit
+	// can still be validated from an activated device. It does not consume
+	// an activation slot.
+	MachineCode string
+
+	// FieldsToReturn control which fields of the license key that is returned.
+	// See https://app.cryptolens.io/docs/api/v3/Validate for more details.
+	FieldsToReturn int
+
+	// SignMethod selects the algorithm used to sign the response. The zero
+	// value defaults to SignMethodRSA; see KeyActivateArguments.SignMethod.
+	SignMethod SignMethod
+}
+
+// HeartbeatEventType describes why a HeartbeatEvent was emitted.
+type HeartbeatEventType int
+
+const (
+	// HeartbeatOK indicates a successful re-validation with no change in
+	// license state.
+	HeartbeatOK HeartbeatEventType = iota
+
+	// HeartbeatExpired indicates that the license has passed its Expires
+	// date.
+	HeartbeatExpired
+
+	// HeartbeatBlocked indicates that the license has been blocked since
+	// the last heartbeat.
+	HeartbeatBlocked
+
+	// HeartbeatOverLimit indicates that the license now reports more
+	// activated machines than MaxNoOfMachines allows, which can happen if
+	// the limit is lowered server-side.
+	HeartbeatOverLimit
+
+	// HeartbeatError indicates that the re-validation request itself
+	// failed, for example due to a network error. Err on the event holds
+	// the underlying error.
+	HeartbeatError
+)
+
+// HeartbeatEvent is sent on the channel returned by LicenseKey.Heartbeat
+// whenever a periodic re-validation completes or fails.
+type HeartbeatEvent struct {
+	// Type describes why this event was emitted.
+	Type HeartbeatEventType
+
+	// LicenseKey is the freshly validated license key. It is the zero value
+	// when Type is HeartbeatError.
+	LicenseKey LicenseKey
+
+	// Err holds the error returned by KeyValidate when Type is
+	// HeartbeatError.
+	Err error
+}
+
+// Heartbeat periodically re-validates licenseKey against the Cryptolens Web
+// API by calling KeyValidateContext every interval, using token and
+// machineCode to identify the license and device. It returns a channel on
+// which a HeartbeatEvent is sent after every check; the channel is closed
+// once ctx is done.
+//
+// If interval is not positive, Heartbeat returns a channel that receives a
+// single HeartbeatError event and is then closed, since time.NewTicker
+// would otherwise panic.
+//
+// This is intended to run for the lifetime of a long-running process, such
+// as a server, so that license revocation, blocking, or expiry can be
+// detected and acted upon without restarting the application.
+func (licenseKey *LicenseKey) Heartbeat(ctx context.Context, token string, machineCode string, interval time.Duration) <-chan HeartbeatEvent {
+	events := make(chan HeartbeatEvent)
+
+	if interval <= 0 {
+		go func() {
+			defer close(events)
+			event := HeartbeatEvent{Type: HeartbeatError, Err: errors.New("cryptolens: Heartbeat interval must be positive")}
+			select {
+			case events <- event:
+			case <-ctx.Done():
+			}
+		}()
+		return events
+	}
+
+	go func() {
+		defer close(events)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				validated, err := KeyValidateContext(ctx, token, KeyValidateArguments{
+					ProductId:   licenseKey.ProductId,
+					Key:         licenseKey.Key,
+					MachineCode: machineCode,
+				})
+
+				var event HeartbeatEvent
+				switch {
+				case err != nil:
+					event = HeartbeatEvent{Type: HeartbeatError, Err: err}
+				case validated.Block:
+					event = HeartbeatEvent{Type: HeartbeatBlocked, LicenseKey: validated}
+				case time.Now().After(validated.Expires):
+					event = HeartbeatEvent{Type: HeartbeatExpired, LicenseKey: validated}
+				case validated.MaxNoOfMachines > 0 && len(validated.ActivatedMachines) > validated.MaxNoOfMachines:
+					event = HeartbeatEvent{Type: HeartbeatOverLimit, LicenseKey: validated}
+				default:
+					event = HeartbeatEvent{Type: HeartbeatOK, LicenseKey: validated}
+				}
+
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return events
+}
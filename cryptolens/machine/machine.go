@@ -0,0 +1,170 @@
+// Package machine generates stable identifiers for the current device,
+// suitable for use as the MachineCode argument to cryptolens.KeyActivate.
+//
+// A fingerprint is produced by hashing together the values returned by a
+// set of Providers, each of which reads a single, reasonably stable
+// component of device identity (a MAC address, the hostname, a platform
+// machine id, and so on). The platform-specific DefaultProviders are used
+// unless the caller supplies its own list via Options, which also makes it
+// possible to mix in application-specific identifiers, such as a Docker
+// container id or a Kubernetes node name, alongside the built-in ones.
+package machine
+
+import (
+	"crypto/sha256"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"runtime"
+	"sort"
+)
+
+// Provider supplies a single component used to build a machine fingerprint.
+type Provider interface {
+	// Name identifies the provider. It is mixed into the hash alongside the
+	// value so that two providers returning the same raw value do not
+	// collide, and is included in error messages.
+	Name() string
+
+	// Value returns the raw bytes for this component. If the component is
+	// not available on the current platform or device, Value should return
+	// a nil slice and a nil error so that Fingerprint can continue using
+	// whatever other providers are configured.
+	Value() ([]byte, error)
+}
+
+// Options controls which Providers are combined into a fingerprint.
+type Options struct {
+	// Providers is the list of components to hash together. If nil, the
+	// current platform's DefaultProviders are used.
+	Providers []Provider
+
+	// Extra holds additional providers appended after Providers. This is
+	// the usual way to mix in application-specific identifiers, such as a
+	// Docker container id or a Kubernetes node name, without having to
+	// replace the platform defaults.
+	Extra []Provider
+}
+
+// Fingerprint generates a stable, deterministic identifier for the current
+// device by hashing together the values returned by the configured
+// Providers. The result is a base32-encoded SHA-256 digest, safe to pass as
+// the MachineCode argument to cryptolens.KeyActivate.
+//
+// Fingerprint returns an error if a provider fails outright, or if none of
+// the configured providers were able to return a value.
+func Fingerprint(opts Options) (string, error) {
+	providers := opts.Providers
+	if providers == nil {
+		providers = DefaultProviders()
+	}
+
+	// Copy before appending so that Extra is never written into spare
+	// capacity of the caller-supplied Providers slice.
+	combined := make([]Provider, 0, len(providers)+len(opts.Extra))
+	combined = append(combined, providers...)
+	combined = append(combined, opts.Extra...)
+	providers = combined
+
+	h := sha256.New()
+	used := 0
+	for _, p := range providers {
+		value, err := p.Value()
+		if err != nil {
+			return "", fmt.Errorf("machine: provider %q: %w", p.Name(), err)
+		}
+		if len(value) == 0 {
+			continue
+		}
+
+		used++
+		fmt.Fprintf(h, "%s\x00", p.Name())
+		h.Write(value)
+		h.Write([]byte{0})
+	}
+
+	if used == 0 {
+		return "", errors.New("machine: no provider returned a value")
+	}
+
+	sum := h.Sum(nil)
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(sum), nil
+}
+
+// StaticProvider is a Provider that always returns a fixed value. It is
+// useful for mixing application-specific identifiers, such as a Docker
+// container id or a Kubernetes node name, into a fingerprint via
+// Options.Extra.
+type StaticProvider struct {
+	// ProviderName identifies this provider; see Provider.Name.
+	ProviderName string
+
+	// ProviderValue is the value returned by Value.
+	ProviderValue string
+}
+
+// Name returns p.ProviderName.
+func (p StaticProvider) Name() string { return p.ProviderName }
+
+// Value returns p.ProviderValue.
+func (p StaticProvider) Value() ([]byte, error) { return []byte(p.ProviderValue), nil }
+
+// hostnameProvider reads the device's hostname.
+type hostnameProvider struct{}
+
+func (hostnameProvider) Name() string { return "hostname" }
+
+func (hostnameProvider) Value() ([]byte, error) {
+	name, err := os.Hostname()
+	if err != nil {
+		return nil, nil
+	}
+	return []byte(name), nil
+}
+
+// osArchProvider reports the operating system and architecture the binary
+// was built for.
+type osArchProvider struct{}
+
+func (osArchProvider) Name() string { return "os-arch" }
+
+func (osArchProvider) Value() ([]byte, error) {
+	return []byte(runtime.GOOS + "/" + runtime.GOARCH), nil
+}
+
+// macAddressProvider collects the hardware addresses of the device's
+// non-loopback network interfaces.
+type macAddressProvider struct{}
+
+func (macAddressProvider) Name() string { return "mac-address" }
+
+func (macAddressProvider) Value() ([]byte, error) {
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return nil, nil
+	}
+
+	var addrs []string
+	for _, iface := range interfaces {
+		if iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addr := iface.HardwareAddr.String()
+		if addr == "" || addr == "00:00:00:00:00:00" {
+			continue
+		}
+		addrs = append(addrs, addr)
+	}
+	if len(addrs) == 0 {
+		return nil, nil
+	}
+
+	sort.Strings(addrs)
+	result := addrs[0]
+	for _, addr := range addrs[1:] {
+		result += "," + addr
+	}
+	return []byte(result), nil
+}
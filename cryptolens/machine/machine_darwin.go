@@ -0,0 +1,47 @@
+//go:build darwin
+// +build darwin
+
+package machine
+
+import (
+	"bytes"
+	"os/exec"
+)
+
+// DefaultProviders returns the Providers used on macOS: the IOPlatformUUID
+// reported by the I/O Kit registry, the hostname, the device's MAC
+// addresses, and the OS/architecture pair.
+func DefaultProviders() []Provider {
+	return []Provider{
+		ioPlatformUUIDProvider{},
+		macAddressProvider{},
+		hostnameProvider{},
+		osArchProvider{},
+	}
+}
+
+// ioPlatformUUIDProvider reads the IOPlatformUUID from the I/O Kit
+// registry, which uniquely identifies the Mac and does not change across
+// reinstalls.
+type ioPlatformUUIDProvider struct{}
+
+func (ioPlatformUUIDProvider) Name() string { return "io-platform-uuid" }
+
+func (ioPlatformUUIDProvider) Value() ([]byte, error) {
+	out, err := exec.Command("ioreg", "-rd1", "-c", "IOPlatformExpertDevice").Output()
+	if err != nil {
+		return nil, nil
+	}
+
+	const marker = "\"IOPlatformUUID\" = \""
+	idx := bytes.Index(out, []byte(marker))
+	if idx == -1 {
+		return nil, nil
+	}
+	rest := out[idx+len(marker):]
+	end := bytes.IndexByte(rest, '"')
+	if end == -1 {
+		return nil, nil
+	}
+	return rest[:end], nil
+}
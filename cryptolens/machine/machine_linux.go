@@ -0,0 +1,57 @@
+//go:build linux
+// +build linux
+
+package machine
+
+import (
+	"bytes"
+	"io/ioutil"
+)
+
+// DefaultProviders returns the Providers used on Linux: the systemd machine
+// id, the DMI product UUID exposed by the kernel, the hostname, the
+// device's MAC addresses, and the OS/architecture pair.
+func DefaultProviders() []Provider {
+	return []Provider{
+		machineIDProvider{},
+		dmiProductUUIDProvider{},
+		macAddressProvider{},
+		hostnameProvider{},
+		osArchProvider{},
+	}
+}
+
+// machineIDProvider reads the machine id maintained by systemd/dbus, which
+// is stable across reboots but unique per install.
+type machineIDProvider struct{}
+
+func (machineIDProvider) Name() string { return "machine-id" }
+
+func (machineIDProvider) Value() ([]byte, error) {
+	paths := []string{"/etc/machine-id", "/var/lib/dbus/machine-id"}
+	for _, path := range paths {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		return bytes.TrimSpace(b), nil
+	}
+	return nil, nil
+}
+
+// dmiProductUUIDProvider reads the product UUID reported by the system's
+// firmware, as exposed by the kernel under /sys/class/dmi. On many systems
+// this file is only readable by root, in which case the provider returns no
+// value rather than an error so fingerprinting can fall back to other
+// components.
+type dmiProductUUIDProvider struct{}
+
+func (dmiProductUUIDProvider) Name() string { return "dmi-product-uuid" }
+
+func (dmiProductUUIDProvider) Value() ([]byte, error) {
+	b, err := ioutil.ReadFile("/sys/class/dmi/id/product_uuid")
+	if err != nil {
+		return nil, nil
+	}
+	return bytes.TrimSpace(b), nil
+}
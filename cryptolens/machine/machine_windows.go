@@ -0,0 +1,47 @@
+//go:build windows
+// +build windows
+
+package machine
+
+import (
+	"bytes"
+	"os/exec"
+)
+
+// DefaultProviders returns the Providers used on Windows: the MachineGuid
+// stored in the registry, the hostname, the device's MAC addresses, and the
+// OS/architecture pair.
+func DefaultProviders() []Provider {
+	return []Provider{
+		machineGUIDProvider{},
+		macAddressProvider{},
+		hostnameProvider{},
+		osArchProvider{},
+	}
+}
+
+// machineGUIDProvider reads MachineGuid from
+// HKLM\SOFTWARE\Microsoft\Cryptography, a value generated once at
+// installation time that persists across reboots.
+type machineGUIDProvider struct{}
+
+func (machineGUIDProvider) Name() string { return "machine-guid" }
+
+func (machineGUIDProvider) Value() ([]byte, error) {
+	out, err := exec.Command("reg", "query", `HKLM\SOFTWARE\Microsoft\Cryptography`, "/v", "MachineGuid").Output()
+	if err != nil {
+		return nil, nil
+	}
+
+	const marker = "MachineGuid"
+	idx := bytes.Index(out, []byte(marker))
+	if idx == -1 {
+		return nil, nil
+	}
+
+	fields := bytes.Fields(out[idx:])
+	if len(fields) < 3 {
+		return nil, nil
+	}
+	return bytes.TrimSpace(fields[len(fields)-1]), nil
+}
@@ -0,0 +1,205 @@
+package cryptolens
+
+import (
+	"bytes"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"io/ioutil"
+	"math"
+	"strings"
+	"time"
+)
+
+// licenseFileMagic identifies the start of a license file produced by
+// ToLicenseFile, so that KeyFromLicenseFile can reject unrelated input
+// early.
+const licenseFileMagic = "CTLF"
+
+// currentLicenseFileVersion is the format version written by
+// ToLicenseFile. KeyFromLicenseFile rejects any other version.
+const currentLicenseFileVersion = 1
+
+var licenseFileEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// ToLicenseFile returns a tamper-evident, paste-friendly representation of
+// the license key: a versioned envelope containing the signing algorithm,
+// the signature, and the signed payload, base32-encoded into a single
+// string. Unlike ToBytes, the signing algorithm travels with the file, so
+// KeyFromLicenseFile can recover it without the caller having to remember
+// which SignMethod was used.
+func (licenseKey *LicenseKey) ToLicenseFile() (string, error) {
+	signMethod := licenseKey.signMethod
+	if signMethod == 0 {
+		signMethod = SignMethodRSA
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(licenseFileMagic)
+	buf.WriteByte(currentLicenseFileVersion)
+	buf.WriteByte(byte(signMethod))
+
+	if err := writeLengthPrefixed(&buf, licenseKey.signatureBytes); err != nil {
+		return "", err
+	}
+	if err := writeLengthPrefixed(&buf, licenseKey.licenseKeyBytes); err != nil {
+		return "", err
+	}
+
+	return licenseFileEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// KeyFromLicenseFile parses a string produced by ToLicenseFile back into a
+// LicenseKey. It does not check the cryptographic signature; use
+// HasValidSignature or HasValidEd25519Signature for that, depending on
+// which algorithm was used to sign the license.
+func KeyFromLicenseFile(s string) (LicenseKey, error) {
+	raw, err := licenseFileEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return LicenseKey{}, err
+	}
+
+	if len(raw) < len(licenseFileMagic)+2 || string(raw[:len(licenseFileMagic)]) != licenseFileMagic {
+		return LicenseKey{}, errors.New("cryptolens: not a valid license file")
+	}
+	raw = raw[len(licenseFileMagic):]
+
+	version := raw[0]
+	if version != currentLicenseFileVersion {
+		return LicenseKey{}, errors.New("cryptolens: unsupported license file version")
+	}
+	signMethod := SignMethod(raw[1])
+	raw = raw[2:]
+
+	signatureBytes, raw, err := readLengthPrefixed(raw)
+	if err != nil {
+		return LicenseKey{}, err
+	}
+	licenseKeyBytes, _, err := readLengthPrefixed(raw)
+	if err != nil {
+		return LicenseKey{}, err
+	}
+
+	return buildLicenseKey(licenseKeyBytes, signatureBytes, signMethod)
+}
+
+func writeLengthPrefixed(buf *bytes.Buffer, data []byte) error {
+	if len(data) > math.MaxUint32 {
+		return errors.New("cryptolens: payload too large for a license file")
+	}
+
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(len(data)))
+	buf.Write(length[:])
+	buf.Write(data)
+	return nil
+}
+
+func readLengthPrefixed(data []byte) ([]byte, []byte, error) {
+	if len(data) < 4 {
+		return nil, nil, errors.New("cryptolens: truncated license file")
+	}
+	length := binary.BigEndian.Uint32(data[:4])
+	data = data[4:]
+	if uint64(len(data)) < uint64(length) {
+		return nil, nil, errors.New("cryptolens: truncated license file")
+	}
+
+	return data[:length], data[length:], nil
+}
+
+// Typed errors returned by CheckOffline, so that callers can tell apart the
+// different reasons an offline check failed and react accordingly.
+var (
+	// ErrExpired indicates that the license key's Expires date has passed.
+	ErrExpired = errors.New("cryptolens: license key has expired")
+
+	// ErrStale indicates that the license key was signed longer ago than
+	// OfflinePolicy.MaxAge allows.
+	ErrStale = errors.New("cryptolens: license key is older than the allowed offline grace period")
+
+	// ErrClockRollback indicates that the system clock reports a time
+	// earlier than the last time CheckOffline ran, suggesting it has been
+	// turned back to defeat expiry or staleness checks.
+	ErrClockRollback = errors.New("cryptolens: system clock appears to have been rolled back")
+
+	// ErrMachineMismatch indicates that OfflinePolicy.MachineCode is not
+	// present in OfflinePolicy.AllowedMachines.
+	ErrMachineMismatch = errors.New("cryptolens: machine code is not in the allowed set")
+)
+
+// OfflinePolicy configures the rules enforced by LicenseKey.CheckOffline
+// when a license is used without being able to reach the Cryptolens Web
+// API.
+type OfflinePolicy struct {
+	// MaxAge is the maximum allowed duration since SignDate before the
+	// license is considered stale. Zero disables the staleness check.
+	MaxAge time.Duration
+
+	// MachineCode is the current device's machine code. It is checked
+	// against AllowedMachines when the latter is non-empty.
+	MachineCode string
+
+	// AllowedMachines, if non-empty, restricts CheckOffline to succeed only
+	// when MachineCode is one of these values.
+	AllowedMachines []string
+
+	// LastSeenPath, if set, names a file used to detect clock rollback. On
+	// every successful check, the current time is recorded there; if a
+	// later check observes a time earlier than the recorded value,
+	// CheckOffline returns ErrClockRollback.
+	LastSeenPath string
+}
+
+// CheckOffline enforces policy against licenseKey without contacting the
+// Cryptolens Web API, returning one of ErrExpired, ErrStale,
+// ErrClockRollback, or ErrMachineMismatch when a rule is violated. It is
+// meant to be called after HasValidSignature (or HasValidEd25519Signature)
+// has confirmed the license has not been tampered with.
+func (licenseKey *LicenseKey) CheckOffline(policy OfflinePolicy) error {
+	now := time.Now()
+
+	if now.After(licenseKey.Expires) {
+		return ErrExpired
+	}
+
+	if policy.MaxAge > 0 && now.Sub(licenseKey.SignDate) > policy.MaxAge {
+		return ErrStale
+	}
+
+	if len(policy.AllowedMachines) > 0 {
+		allowed := false
+		for _, machineCode := range policy.AllowedMachines {
+			if machineCode == policy.MachineCode {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return ErrMachineMismatch
+		}
+	}
+
+	if policy.LastSeenPath != "" {
+		if err := checkClockRollback(policy.LastSeenPath, now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkClockRollback compares now against the timestamp recorded at path,
+// returning ErrClockRollback if now is earlier, and otherwise updates path
+// to record now for the next check.
+func checkClockRollback(path string, now time.Time) error {
+	if data, err := ioutil.ReadFile(path); err == nil {
+		if lastSeen, err := time.Parse(time.RFC3339, strings.TrimSpace(string(data))); err == nil {
+			if now.Before(lastSeen) {
+				return ErrClockRollback
+			}
+		}
+	}
+
+	return ioutil.WriteFile(path, []byte(now.Format(time.RFC3339)), 0600)
+}